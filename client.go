@@ -2,6 +2,7 @@ package rudder
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/json"
@@ -17,8 +18,10 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/Unknwon/com"
+	"github.com/docker/libtrust"
 )
 
 var (
@@ -33,8 +36,23 @@ type Client struct {
 	HTTPClient *http.Client
 	TLSConfig  *tls.Config
 
+	// MetaHeaders are set on every request this client makes, over both
+	// the HTTP(S) and unix-socket transports. Over HTTP(S) they are added by
+	// the Transport that NewClient and NewTLSClient install, which holds the
+	// same map; a map assigned to this field later reaches only unix-socket
+	// requests.
+	MetaHeaders http.Header
+
+	// TrustKey, when set, is used to sign pushed images and to identify
+	// this client to servers that support content trust. See
+	// LoadOrCreateTrustKey.
+	TrustKey libtrust.PrivateKey
+
 	endpoint    string
 	endpointURL *url.URL
+
+	eventMonitorLock sync.Mutex
+	eventMonitor     *eventMonitor
 }
 
 func parseEndpoint(endpoint string) (*url.URL, error) {
@@ -88,8 +106,10 @@ func NewClient(endpoint string) (*Client, error) {
 	if err != nil {
 		return nil, err
 	}
+	meta := make(http.Header)
 	return &Client{
-		HTTPClient:  http.DefaultClient,
+		HTTPClient:  &http.Client{Transport: &Transport{MetaHeaders: meta}},
+		MetaHeaders: meta,
 		endpoint:    endpoint,
 		endpointURL: u,
 	}, nil
@@ -129,9 +149,11 @@ func NewTLSClient(endpoint, cert, key, ca string) (*Client, error) {
 	if err != nil {
 		return nil, err
 	}
+	meta := make(http.Header)
 	return &Client{
-		HTTPClient:  &http.Client{Transport: tr},
+		HTTPClient:  &http.Client{Transport: &Transport{Base: tr, MetaHeaders: meta}},
 		TLSConfig:   tlsConfig,
+		MetaHeaders: meta,
 		endpoint:    endpoint,
 		endpointURL: u,
 	}, nil
@@ -165,17 +187,21 @@ type jsonMessage struct {
 	Stream   string `json:"stream,omitempty"`
 }
 
-func (c *Client) stream(method, path string, setRawTerminal, rawJSONStream bool, headers map[string]string, in io.Reader, stdout, stderr io.Writer) error {
+// newStreamResponse issues a request and returns the live, still-open
+// response so the caller can read its body as a stream. It centralizes
+// request construction and the unix-vs-HTTP dialing choice so that
+// long-running consumers (stream's own /build handling, and the /events
+// listener) don't have to duplicate it.
+func (c *Client) newStreamResponse(ctx context.Context, method, path string, headers map[string]string, in io.Reader) (*http.Response, error) {
 	log.Printf("%s %s", method, path)
 
 	if (method == "POST" || method == "PUT") && in == nil {
 		in = bytes.NewReader(nil)
 	}
-	req, err := http.NewRequest(method, c.getURL(path), in)
+	req, err := http.NewRequestWithContext(ctx, method, c.getURL(path), in)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	req.Header.Set("User-Agent", _USER_AGENT)
 	if method == "POST" {
 		req.Header.Set("Content-Type", "plain/text")
 	}
@@ -183,39 +209,59 @@ func (c *Client) stream(method, path string, setRawTerminal, rawJSONStream bool,
 		req.Header.Set(key, val)
 	}
 	var resp *http.Response
-	protocol := c.endpointURL.Scheme
-	address := c.endpointURL.Path
-	if stdout == nil {
-		stdout = ioutil.Discard
-	}
-	if stderr == nil {
-		stderr = ioutil.Discard
-	}
-	if protocol == "unix" {
-		dial, err := net.Dial(protocol, address)
-		if err != nil {
-			return err
-		}
-		clientconn := httputil.NewClientConn(dial, nil)
-		resp, err = clientconn.Do(req)
-		defer clientconn.Close()
+	if c.endpointURL.Scheme == "unix" {
+		resp, err = c.doUnix(ctx, req)
 	} else {
 		resp, err = c.HTTPClient.Do(req)
 	}
 	if err != nil {
 		if strings.Contains(err.Error(), "connection refused") {
-			return ErrConnectionRefused
+			return nil, ErrConnectionRefused
 		}
-		return err
+		return nil, err
 	}
-	defer resp.Body.Close()
 	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+		defer resp.Body.Close()
 		body, err := ioutil.ReadAll(resp.Body)
 		if err != nil {
+			return nil, err
+		}
+		return nil, newError(resp.StatusCode, body)
+	}
+	return resp, nil
+}
+
+// decodeJSONStream decodes a newline-delimited JSON stream, invoking fn
+// with each raw message in turn, until the stream is exhausted or fn
+// returns an error. Both /build's progress stream and the /events listener
+// read newline-delimited JSON, so they share this decode loop.
+func decodeJSONStream(r io.Reader, fn func(json.RawMessage) error) error {
+	dec := json.NewDecoder(r)
+	for {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if err := fn(raw); err != nil {
 			return err
 		}
-		return newError(resp.StatusCode, body)
 	}
+}
+
+func (c *Client) stream(ctx context.Context, method, path string, setRawTerminal, rawJSONStream bool, headers map[string]string, in io.Reader, stdout, stderr io.Writer) error {
+	if stdout == nil {
+		stdout = ioutil.Discard
+	}
+	if stderr == nil {
+		stderr = ioutil.Discard
+	}
+	resp, err := c.newStreamResponse(ctx, method, path, headers, in)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
 	if resp.Header.Get("Content-Type") == "application/json" {
 		// if we want to get raw json stream, just copy it back to output
 		// without decoding it
@@ -223,12 +269,9 @@ func (c *Client) stream(method, path string, setRawTerminal, rawJSONStream bool,
 			_, err = io.Copy(stdout, resp.Body)
 			return err
 		}
-		dec := json.NewDecoder(resp.Body)
-		for {
+		return decodeJSONStream(resp.Body, func(raw json.RawMessage) error {
 			var m jsonMessage
-			if err := dec.Decode(&m); err == io.EOF {
-				break
-			} else if err != nil {
+			if err := json.Unmarshal(raw, &m); err != nil {
 				return err
 			}
 			if m.Stream != "" {
@@ -241,16 +284,121 @@ func (c *Client) stream(method, path string, setRawTerminal, rawJSONStream bool,
 			if m.Status != "" {
 				fmt.Fprintln(stdout, m.Status)
 			}
+			return nil
+		})
+	}
+	if setRawTerminal {
+		_, err = io.Copy(stdout, resp.Body)
+	} else {
+		_, err = stdCopy(stdout, stderr, resp.Body)
+	}
+	return err
+}
+
+// do sends an HTTP request to the Docker daemon and returns the response
+// body along with the status code. Unlike stream, it is meant for calls
+// that return a single JSON document rather than a chunked or hijacked
+// stream. The request is bound to ctx so callers can cancel it or attach a
+// deadline.
+func (c *Client) do(ctx context.Context, method, path string, data interface{}) ([]byte, int, error) {
+	var params io.Reader
+	if data != nil {
+		buf, err := json.Marshal(data)
+		if err != nil {
+			return nil, -1, err
 		}
+		params = bytes.NewBuffer(buf)
+	}
+
+	log.Printf("%s %s", method, path)
+
+	req, err := http.NewRequestWithContext(ctx, method, c.getURL(path), params)
+	if err != nil {
+		return nil, -1, err
+	}
+	if data != nil {
+		req.Header.Set("Content-Type", "application/json")
+	} else if method == "POST" {
+		req.Header.Set("Content-Type", "plain/text")
+	}
+
+	var resp *http.Response
+	if c.endpointURL.Scheme == "unix" {
+		resp, err = c.doUnix(ctx, req)
 	} else {
-		if setRawTerminal {
-			_, err = io.Copy(stdout, resp.Body)
-		} else {
-			_, err = stdCopy(stdout, stderr, resp.Body)
+		resp, err = c.HTTPClient.Do(req)
+	}
+	if err != nil {
+		if strings.Contains(err.Error(), "connection refused") {
+			return nil, -1, ErrConnectionRefused
 		}
-		return err
+		return nil, -1, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, -1, err
 	}
-	return nil
+	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+		return nil, resp.StatusCode, newError(resp.StatusCode, body)
+	}
+	return body, resp.StatusCode, nil
+}
+
+// doUnix issues req over a raw unix-socket connection, bypassing
+// c.HTTPClient (and therefore its Transport), since httputil.ClientConn
+// doesn't know how to dial unix sockets itself. It sets the headers the
+// Transport would otherwise add and honors ctx cancellation by closing the
+// connection if ctx is done before the call returns.
+func (c *Client) doUnix(ctx context.Context, req *http.Request) (*http.Response, error) {
+	req.Header.Set("User-Agent", _USER_AGENT)
+	for key, values := range c.MetaHeaders {
+		for _, v := range values {
+			req.Header.Add(key, v)
+		}
+	}
+
+	dial, err := (&net.Dialer{}).DialContext(ctx, "unix", c.endpointURL.Path)
+	if err != nil {
+		return nil, err
+	}
+	clientconn := httputil.NewClientConn(dial, nil)
+
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			clientconn.Close()
+		case <-done:
+		}
+	}()
+
+	resp, err := clientconn.Do(req)
+	if err != nil {
+		close(done)
+		clientconn.Close()
+		return nil, err
+	}
+	// resp.Body reads off clientconn's underlying connection, so it must
+	// stay open until the body has been fully consumed and closed.
+	resp.Body = closeWithConn{resp.Body, clientconn, done}
+	return resp, nil
+}
+
+// closeWithConn closes both the HTTP body and the connection it was read
+// from (and stops the ctx-cancellation watcher goroutine) when Close is
+// called, so callers only ever need to close resp.Body.
+type closeWithConn struct {
+	io.ReadCloser
+	conn interface{ Close() error }
+	done chan struct{}
+}
+
+func (c closeWithConn) Close() error {
+	defer close(c.done)
+	defer c.conn.Close()
+	return c.ReadCloser.Close()
 }
 
 func queryString(opts interface{}) string {
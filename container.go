@@ -0,0 +1,419 @@
+package rudder
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ErrContainerNotFound is returned when a given container does not exist.
+var ErrContainerNotFound = errors.New("no such container")
+
+// ErrExecNotFound is returned when a given exec instance does not exist.
+var ErrExecNotFound = errors.New("no such exec instance")
+
+// Config holds the configuration of a container, i.e. the configuration
+// that is portable between hosts.
+//
+// http://goo.gl/GNG7nm
+type Config struct {
+	Hostname        string              `json:"Hostname,omitempty"`
+	Domainname      string              `json:"Domainname,omitempty"`
+	User            string              `json:"User,omitempty"`
+	Memory          int64               `json:"Memory,omitempty"`
+	MemorySwap      int64               `json:"MemorySwap,omitempty"`
+	CPUShares       int64               `json:"CpuShares,omitempty"`
+	CPUSet          string              `json:"Cpuset,omitempty"`
+	AttachStdin     bool                `json:"AttachStdin,omitempty"`
+	AttachStdout    bool                `json:"AttachStdout,omitempty"`
+	AttachStderr    bool                `json:"AttachStderr,omitempty"`
+	PortSpecs       []string            `json:"PortSpecs,omitempty"`
+	ExposedPorts    map[string]struct{} `json:"ExposedPorts,omitempty"`
+	Tty             bool                `json:"Tty,omitempty"`
+	OpenStdin       bool                `json:"OpenStdin,omitempty"`
+	StdinOnce       bool                `json:"StdinOnce,omitempty"`
+	Env             []string            `json:"Env,omitempty"`
+	Cmd             []string            `json:"Cmd"`
+	Image           string              `json:"Image,omitempty"`
+	Volumes         map[string]struct{} `json:"Volumes,omitempty"`
+	WorkingDir      string              `json:"WorkingDir,omitempty"`
+	Entrypoint      []string            `json:"Entrypoint"`
+	NetworkDisabled bool                `json:"NetworkDisabled,omitempty"`
+	Labels          map[string]string   `json:"Labels,omitempty"`
+}
+
+// HostConfig holds the configuration of a container that is specific to the
+// host it runs on, i.e. things that don't travel with the container image.
+//
+// http://goo.gl/6vuhkH
+type HostConfig struct {
+	Binds           []string          `json:"Binds,omitempty"`
+	Links           []string          `json:"Links,omitempty"`
+	Memory          int64             `json:"Memory,omitempty"`
+	MemorySwap      int64             `json:"MemorySwap,omitempty"`
+	CPUShares       int64             `json:"CpuShares,omitempty"`
+	CPUSet          string            `json:"CpusetCpus,omitempty"`
+	PortBindings    map[string][]Port `json:"PortBindings,omitempty"`
+	PublishAllPorts bool              `json:"PublishAllPorts,omitempty"`
+	Privileged      bool              `json:"Privileged,omitempty"`
+	ReadonlyRootfs  bool              `json:"ReadonlyRootfs,omitempty"`
+	Dns             []string          `json:"Dns,omitempty"`
+	DnsSearch       []string          `json:"DnsSearch,omitempty"`
+	ExtraHosts      []string          `json:"ExtraHosts,omitempty"`
+	VolumesFrom     []string          `json:"VolumesFrom,omitempty"`
+	NetworkMode     string            `json:"NetworkMode,omitempty"`
+	Labels          map[string]string `json:"Labels,omitempty"`
+}
+
+// Port represents the host side of a port binding; HostConfig.PortBindings
+// keys it by container port, e.g. "1234/tcp".
+type Port struct {
+	HostIP   string `json:"HostIp,omitempty"`
+	HostPort string `json:"HostPort,omitempty"`
+}
+
+// Container represents a Docker container, as returned by InspectContainer.
+//
+// http://goo.gl/XYoYcN
+type Container struct {
+	ID      string `json:"Id"`
+	Created string `json:"Created,omitempty"`
+	Path    string `json:"Path,omitempty"`
+	Args    []string
+
+	Config *Config
+	State  struct {
+		Running    bool   `json:"Running,omitempty"`
+		Paused     bool   `json:"Paused,omitempty"`
+		Restarting bool   `json:"Restarting,omitempty"`
+		Pid        int    `json:"Pid,omitempty"`
+		ExitCode   int    `json:"ExitCode,omitempty"`
+		StartedAt  string `json:"StartedAt,omitempty"`
+		FinishedAt string `json:"FinishedAt,omitempty"`
+	} `json:"State,omitempty"`
+	Image      string `json:"Image,omitempty"`
+	Name       string `json:"Name,omitempty"`
+	HostConfig *HostConfig
+}
+
+// APIContainers represents a container as listed by ListContainers.
+//
+// http://goo.gl/kaOHGw
+type APIContainers struct {
+	ID         string            `json:"Id"`
+	Image      string            `json:"Image,omitempty"`
+	Command    string            `json:"Command,omitempty"`
+	Created    int64             `json:"Created,omitempty"`
+	Status     string            `json:"Status,omitempty"`
+	Ports      []Port            `json:"Ports,omitempty"`
+	SizeRw     int64             `json:"SizeRw,omitempty"`
+	SizeRootFs int64             `json:"SizeRootFs,omitempty"`
+	Names      []string          `json:"Names,omitempty"`
+	Labels     map[string]string `json:"Labels,omitempty"`
+}
+
+// ListContainersOptions specifies parameters to the ListContainers method.
+//
+// http://goo.gl/kaOHGw
+type ListContainersOptions struct {
+	All     bool                `qs:"all"`
+	Size    bool                `qs:"size"`
+	Limit   int                 `qs:"limit"`
+	Since   string              `qs:"since"`
+	Before  string              `qs:"before"`
+	Filters map[string][]string `qs:"filters"`
+}
+
+// ListContainers returns a slice of containers matching the given criteria.
+//
+// http://goo.gl/kaOHGw
+func (c *Client) ListContainers(opts ListContainersOptions) ([]APIContainers, error) {
+	path := "/containers/json?" + queryString(&opts)
+	body, _, err := c.do(context.Background(), "GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	var containers []APIContainers
+	if err := json.Unmarshal(body, &containers); err != nil {
+		return nil, err
+	}
+	return containers, nil
+}
+
+// CreateContainerOptions specifies parameters to the CreateContainer method.
+//
+// http://goo.gl/WqzHBs
+type CreateContainerOptions struct {
+	Name       string `qs:"-"`
+	Config     *Config
+	HostConfig *HostConfig
+}
+
+// CreateContainer creates a new container, returning it as a *Container.
+//
+// http://goo.gl/WqzHBs
+func (c *Client) CreateContainer(opts CreateContainerOptions) (*Container, error) {
+	path := "/containers/create?" + queryString(struct {
+		Name string `qs:"name"`
+	}{opts.Name})
+	body, status, err := c.do(context.Background(), "POST", path, struct {
+		*Config
+		HostConfig *HostConfig
+	}{opts.Config, opts.HostConfig})
+	if status == http.StatusNotFound {
+		return nil, errors.New("no such image")
+	}
+	if err != nil {
+		return nil, err
+	}
+	var created struct {
+		ID       string `json:"Id"`
+		Warnings []string
+	}
+	if err := json.Unmarshal(body, &created); err != nil {
+		return nil, err
+	}
+	return c.InspectContainer(created.ID)
+}
+
+// InspectContainer returns information about a container by its ID.
+//
+// http://goo.gl/XYoYcN
+func (c *Client) InspectContainer(id string) (*Container, error) {
+	body, status, err := c.do(context.Background(), "GET", "/containers/"+id+"/json", nil)
+	if status == http.StatusNotFound {
+		return nil, ErrContainerNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var container Container
+	if err := json.Unmarshal(body, &container); err != nil {
+		return nil, err
+	}
+	return &container, nil
+}
+
+// StartContainer starts a container, optionally overriding its HostConfig.
+//
+// http://goo.gl/fLywVg
+func (c *Client) StartContainer(id string, hostConfig *HostConfig) error {
+	_, status, err := c.do(context.Background(), "POST", "/containers/"+id+"/start", hostConfig)
+	if status == http.StatusNotFound {
+		return ErrContainerNotFound
+	}
+	if status == http.StatusNotModified {
+		return nil
+	}
+	return err
+}
+
+// StopContainer stops a container, killing it after timeout seconds if it
+// doesn't stop on its own.
+//
+// http://goo.gl/USqsFt
+func (c *Client) StopContainer(id string, timeout uint) error {
+	path := fmt.Sprintf("/containers/%s/stop?t=%d", id, timeout)
+	_, status, err := c.do(context.Background(), "POST", path, nil)
+	if status == http.StatusNotFound {
+		return ErrContainerNotFound
+	}
+	if status == http.StatusNotModified {
+		return nil
+	}
+	return err
+}
+
+// RestartContainer restarts a container, killing it after timeout seconds if
+// it doesn't stop on its own.
+//
+// http://goo.gl/MrAKQ5
+func (c *Client) RestartContainer(id string, timeout uint) error {
+	path := fmt.Sprintf("/containers/%s/restart?t=%d", id, timeout)
+	_, status, err := c.do(context.Background(), "POST", path, nil)
+	if status == http.StatusNotFound {
+		return ErrContainerNotFound
+	}
+	return err
+}
+
+// KillContainer sends a signal to a container, defaulting to SIGKILL if
+// signal is empty.
+//
+// http://goo.gl/h8ZYPV
+func (c *Client) KillContainer(id, signal string) error {
+	path := "/containers/" + id + "/kill"
+	if signal != "" {
+		path += "?signal=" + signal
+	}
+	_, status, err := c.do(context.Background(), "POST", path, nil)
+	if status == http.StatusNotFound {
+		return ErrContainerNotFound
+	}
+	return err
+}
+
+// RemoveContainerOptions specifies parameters to the RemoveContainer method.
+//
+// http://goo.gl/RQyX62
+type RemoveContainerOptions struct {
+	ID            string `qs:"-"`
+	RemoveVolumes bool   `qs:"v"`
+	Force         bool   `qs:"force"`
+}
+
+// RemoveContainer removes a container, optionally removing its volumes and
+// forcing removal of a running one.
+//
+// http://goo.gl/RQyX62
+func (c *Client) RemoveContainer(opts RemoveContainerOptions) error {
+	path := "/containers/" + opts.ID + "?" + queryString(&opts)
+	_, status, err := c.do(context.Background(), "DELETE", path, nil)
+	if status == http.StatusNotFound {
+		return ErrContainerNotFound
+	}
+	return err
+}
+
+// WaitContainer blocks until a container stops, returning its exit code.
+//
+// http://goo.gl/J88DHU
+func (c *Client) WaitContainer(id string) (int, error) {
+	body, status, err := c.do(context.Background(), "POST", "/containers/"+id+"/wait", nil)
+	if status == http.StatusNotFound {
+		return 0, ErrContainerNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+	var result struct{ StatusCode int }
+	if err := json.Unmarshal(body, &result); err != nil {
+		return 0, err
+	}
+	return result.StatusCode, nil
+}
+
+// CreateExecOptions specifies parameters to the CreateExec method.
+//
+// http://goo.gl/1KSIb7
+type CreateExecOptions struct {
+	Container    string   `qs:"-" json:"-"`
+	AttachStdin  bool     `json:"AttachStdin,omitempty"`
+	AttachStdout bool     `json:"AttachStdout,omitempty"`
+	AttachStderr bool     `json:"AttachStderr,omitempty"`
+	Tty          bool     `json:"Tty,omitempty"`
+	Cmd          []string `json:"Cmd,omitempty"`
+}
+
+// Exec represents an exec instance created in a container.
+type Exec struct {
+	ID string `json:"Id"`
+}
+
+// CreateExec creates a new exec instance in a running container.
+//
+// http://goo.gl/1KSIb7
+func (c *Client) CreateExec(opts CreateExecOptions) (*Exec, error) {
+	body, status, err := c.do(context.Background(), "POST", "/containers/"+opts.Container+"/exec", opts)
+	if status == http.StatusNotFound {
+		return nil, ErrContainerNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var exec Exec
+	if err := json.Unmarshal(body, &exec); err != nil {
+		return nil, err
+	}
+	return &exec, nil
+}
+
+// StartExecOptions specifies parameters to the StartExec method.
+type StartExecOptions struct {
+	Detach bool `json:"Detach,omitempty"`
+	Tty    bool `json:"Tty,omitempty"`
+
+	InputStream  io.Reader `json:"-"`
+	OutputStream io.Writer `json:"-"`
+	ErrorStream  io.Writer `json:"-"`
+
+	RawTerminal bool `json:"-"`
+
+	// Context, if set, is used to cancel the attached stdio stream. If
+	// nil, context.Background() is used.
+	Context context.Context `json:"-"`
+}
+
+// StartExec starts a previously created exec instance. Unless Detach is set,
+// the exec's output is streamed to OutputStream and ErrorStream through
+// Client.stream; InputStream is not sent to the exec.
+//
+// http://goo.gl/uFIHzv
+func (c *Client) StartExec(id string, opts StartExecOptions) error {
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	headers := map[string]string{"Content-Type": "application/json"}
+	body, err := json.Marshal(opts)
+	if err != nil {
+		return err
+	}
+	if opts.Detach {
+		_, status, err := c.do(ctx, "POST", "/exec/"+id+"/start", opts)
+		if status == http.StatusNotFound {
+			return ErrExecNotFound
+		}
+		return err
+	}
+	return c.stream(ctx, "POST", "/exec/"+id+"/start", opts.RawTerminal, false, headers,
+		bytes.NewReader(body), opts.OutputStream, opts.ErrorStream)
+}
+
+// InspectExec returns low-level information about an exec instance.
+//
+// http://goo.gl/6IlWAl
+func (c *Client) InspectExec(id string) (*ExecInspect, error) {
+	body, status, err := c.do(context.Background(), "GET", "/exec/"+id+"/json", nil)
+	if status == http.StatusNotFound {
+		return nil, ErrExecNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var info ExecInspect
+	if err := json.Unmarshal(body, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// ExecInspect holds the result of InspectExec.
+//
+// http://goo.gl/6IlWAl
+type ExecInspect struct {
+	ID        string `json:"ID"`
+	Running   bool   `json:"Running"`
+	ExitCode  int    `json:"ExitCode"`
+	ProcessConfig struct {
+		Tty        bool     `json:"tty"`
+		Entrypoint string   `json:"entrypoint"`
+		Arguments  []string `json:"arguments"`
+	} `json:"ProcessConfig"`
+}
+
+// ResizeExecTTY resizes the TTY of a running exec instance.
+//
+// http://goo.gl/tHqbEE
+func (c *Client) ResizeExecTTY(id string, height, width int) error {
+	path := fmt.Sprintf("/exec/%s/resize?h=%d&w=%d", id, height, width)
+	_, status, err := c.do(context.Background(), "POST", path, nil)
+	if status == http.StatusNotFound {
+		return ErrExecNotFound
+	}
+	return err
+}
@@ -0,0 +1,219 @@
+package rudder
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"os/user"
+	"path"
+	"strings"
+)
+
+// ErrCannotParseDockercfg is returned when a .docker/config.json or
+// .dockercfg file cannot be parsed.
+var ErrCannotParseDockercfg = errors.New("failed to read authentication from dockercfg")
+
+// dockerConfigEntry is a single entry of the "auths" map, either in the
+// current config.json or the legacy .dockercfg format.
+type dockerConfigEntry struct {
+	Auth  string `json:"auth"`
+	Email string `json:"email,omitempty"`
+}
+
+// NewAuthConfigurationsFromDockerCfg loads the registry credentials stored
+// in the default locations used by the docker client: first
+// ~/.docker/config.json, falling back to the legacy ~/.dockercfg.
+func NewAuthConfigurationsFromDockerCfg() (*AuthConfigurations, error) {
+	home := homeDir()
+	if home == "" {
+		return nil, errors.New("cannot determine user's home directory")
+	}
+	if path, err := findFirstExisting(
+		path.Join(home, ".docker", "config.json"),
+		path.Join(home, ".dockercfg"),
+	); err == nil {
+		return NewAuthConfigurationsFromFile(path)
+	}
+	return nil, ErrCannotParseDockercfg
+}
+
+// NewAuthConfigurationsFromFile loads registry credentials from a
+// dockercfg-style JSON file at the given path, resolving entries backed by
+// credsStore/credHelpers through the credential-helper protocol.
+func NewAuthConfigurationsFromFile(path string) (*AuthConfigurations, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// config.json is an object with a top-level "auths" key (plus
+	// optional "credsStore"/"credHelpers"); the legacy .dockercfg is just
+	// the "auths" map itself, with no wrapping object. Distinguish the two
+	// by checking for the "auths" key rather than by unmarshal success,
+	// since a flat .dockercfg unmarshals into an empty top-level map
+	// without error and would otherwise silently yield zero credentials.
+	var top map[string]json.RawMessage
+	if err := json.Unmarshal(data, &top); err != nil {
+		return nil, ErrCannotParseDockercfg
+	}
+
+	auths := &AuthConfigurations{Configs: make(map[string]AuthConfiguration)}
+
+	authsRaw, isConfigJSON := top["auths"]
+	if !isConfigJSON {
+		authsRaw = data
+	}
+	var entries map[string]dockerConfigEntry
+	if err := json.Unmarshal(authsRaw, &entries); err != nil {
+		return nil, ErrCannotParseDockercfg
+	}
+	for registry, entry := range entries {
+		auth, err := decodeAuthEntry(registry, entry)
+		if err != nil {
+			return nil, err
+		}
+		auths.Configs[registry] = auth
+	}
+	if !isConfigJSON {
+		return auths, nil
+	}
+
+	var credHelpers map[string]string
+	if raw, ok := top["credHelpers"]; ok {
+		if err := json.Unmarshal(raw, &credHelpers); err != nil {
+			return nil, ErrCannotParseDockercfg
+		}
+	}
+	for registry, helper := range credHelpers {
+		auth, err := authFromHelper(helper, registry)
+		if err != nil {
+			return nil, err
+		}
+		auths.Configs[registry] = auth
+	}
+
+	var credsStore string
+	if raw, ok := top["credsStore"]; ok {
+		if err := json.Unmarshal(raw, &credsStore); err != nil {
+			return nil, ErrCannotParseDockercfg
+		}
+	}
+	if credsStore != "" {
+		// credsStore-only configs commonly have no "auths" entries at all
+		// (credentials live purely in the OS keychain), so the set of
+		// registries has to come from the helper itself, not from auths.
+		registries, err := listHelperRegistries(credsStore)
+		if err != nil {
+			return nil, err
+		}
+		for _, registry := range registries {
+			if _, already := credHelpers[registry]; already {
+				continue
+			}
+			auth, err := authFromHelper(credsStore, registry)
+			if err != nil {
+				return nil, err
+			}
+			auths.Configs[registry] = auth
+		}
+	}
+	return auths, nil
+}
+
+func decodeAuthEntry(registry string, entry dockerConfigEntry) (AuthConfiguration, error) {
+	auth := AuthConfiguration{Email: entry.Email, ServerAddress: registry}
+	if entry.Auth == "" {
+		return auth, nil
+	}
+	decoded, err := base64.StdEncoding.DecodeString(entry.Auth)
+	if err != nil {
+		return auth, ErrCannotParseDockercfg
+	}
+	parts := strings.SplitN(string(decoded), ":", 2)
+	if len(parts) != 2 {
+		return auth, ErrCannotParseDockercfg
+	}
+	auth.Username = parts[0]
+	auth.Password = parts[1]
+	return auth, nil
+}
+
+// credHelperResponse is the JSON a credential helper's "get" command writes
+// to stdout: https://github.com/docker/docker-credential-helpers
+type credHelperResponse struct {
+	ServerURL string `json:"ServerURL"`
+	Username  string `json:"Username"`
+	Secret    string `json:"Secret"`
+}
+
+// authFromHelper shells out to docker-credential-<helper> get, writing the
+// registry server address to stdin and parsing the JSON credential back
+// from stdout, per the credential-helper protocol.
+func authFromHelper(helper, registry string) (AuthConfiguration, error) {
+	var auth AuthConfiguration
+	bin := "docker-credential-" + helper
+	cmd := exec.Command(bin, "get")
+	cmd.Stdin = strings.NewReader(registry)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	if err := cmd.Run(); err != nil {
+		return auth, fmt.Errorf("%s get: %v", bin, err)
+	}
+	var resp credHelperResponse
+	if err := json.Unmarshal(out.Bytes(), &resp); err != nil {
+		return auth, fmt.Errorf("%s get: %v", bin, err)
+	}
+	auth.Username = resp.Username
+	auth.Password = resp.Secret
+	auth.ServerAddress = registry
+	if resp.ServerURL != "" {
+		auth.ServerAddress = resp.ServerURL
+	}
+	return auth, nil
+}
+
+// listHelperRegistries runs docker-credential-<helper> list, which returns a
+// JSON object mapping each registry it holds credentials for to the stored
+// username, and returns its registry keys.
+func listHelperRegistries(helper string) ([]string, error) {
+	bin := "docker-credential-" + helper
+	cmd := exec.Command(bin, "list")
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%s list: %v", bin, err)
+	}
+	var listing map[string]string
+	if err := json.Unmarshal(out.Bytes(), &listing); err != nil {
+		return nil, fmt.Errorf("%s list: %v", bin, err)
+	}
+	registries := make([]string, 0, len(listing))
+	for registry := range listing {
+		registries = append(registries, registry)
+	}
+	return registries, nil
+}
+
+func findFirstExisting(paths ...string) (string, error) {
+	for _, p := range paths {
+		if _, err := os.Stat(p); err == nil {
+			return p, nil
+		}
+	}
+	return "", os.ErrNotExist
+}
+
+func homeDir() string {
+	if home := os.Getenv("HOME"); home != "" {
+		return home
+	}
+	if u, err := user.Current(); err == nil {
+		return u.HomeDir
+	}
+	return ""
+}
@@ -0,0 +1,179 @@
+package rudder
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// ErrEventsOptionsConflict is returned by AddEventListenerWithOptions when
+// the requested options don't match the filter an already-running /events
+// listener started with. Only one filter can be in effect per Client at a
+// time, since all listeners share a single underlying connection.
+var ErrEventsOptionsConflict = errors.New("events listener already running with different options")
+
+// APIEvents represents an event returned by the Docker API on the /events
+// endpoint.
+//
+// http://goo.gl/EPc8XT
+type APIEvents struct {
+	Status   string   `json:"Status,omitempty"`
+	ID       string   `json:"id,omitempty"`
+	From     string   `json:"from,omitempty"`
+	Type     string   `json:"Type,omitempty"`
+	Action   string   `json:"Action,omitempty"`
+	Actor    APIActor `json:"Actor,omitempty"`
+	Time     int64    `json:"time,omitempty"`
+	TimeNano int64    `json:"timeNano,omitempty"`
+}
+
+// APIActor describes the object that produced an event.
+type APIActor struct {
+	ID         string            `json:"ID,omitempty"`
+	Attributes map[string]string `json:"Attributes,omitempty"`
+}
+
+// EventsOptions specifies parameters to AddEventListener's underlying
+// /events request.
+//
+// http://goo.gl/EPc8XT
+type EventsOptions struct {
+	Since   string              `qs:"since"`
+	Until   string              `qs:"until"`
+	Filters map[string][]string `qs:"filters"`
+}
+
+const eventsReconnectMaxBackoff = 30 * time.Second
+
+// isZeroEventsOptions reports whether opts is the zero value, i.e. the
+// caller didn't ask for any particular filter and is happy to join
+// whatever listener is already running.
+func isZeroEventsOptions(opts EventsOptions) bool {
+	return reflect.DeepEqual(opts, EventsOptions{})
+}
+
+// eventMonitor owns the single long-lived /events connection shared by all
+// registered listener channels, reconnecting on transport errors and
+// tearing itself down once the last listener unregisters.
+type eventMonitor struct {
+	sync.Mutex
+	listeners []chan<- *APIEvents
+	opts      EventsOptions
+	cancel    context.CancelFunc
+	lastSeen  time.Time
+}
+
+// AddEventListener registers listener to receive a copy of every event the
+// daemon reports, starting (and, if necessary, reconnecting) the underlying
+// /events stream as needed. Events are sent without blocking: an event is
+// dropped for a listener whose channel is not ready to receive it.
+func (c *Client) AddEventListener(listener chan<- *APIEvents) error {
+	return c.AddEventListenerWithOptions(EventsOptions{}, listener)
+}
+
+// AddEventListenerWithOptions is like AddEventListener but lets the caller
+// filter the event stream.
+func (c *Client) AddEventListenerWithOptions(opts EventsOptions, listener chan<- *APIEvents) error {
+	c.eventMonitorLock.Lock()
+	defer c.eventMonitorLock.Unlock()
+
+	if c.eventMonitor == nil {
+		m := &eventMonitor{opts: opts}
+		ctx, cancel := context.WithCancel(context.Background())
+		m.cancel = cancel
+		c.eventMonitor = m
+		go c.runEventMonitor(ctx, m)
+	} else if !isZeroEventsOptions(opts) && !reflect.DeepEqual(opts, c.eventMonitor.opts) {
+		return ErrEventsOptionsConflict
+	}
+	c.eventMonitor.Lock()
+	c.eventMonitor.listeners = append(c.eventMonitor.listeners, listener)
+	c.eventMonitor.Unlock()
+	return nil
+}
+
+// RemoveEventListener unregisters listener. Once the last listener is
+// removed, the underlying /events connection is closed.
+func (c *Client) RemoveEventListener(listener chan<- *APIEvents) error {
+	c.eventMonitorLock.Lock()
+	defer c.eventMonitorLock.Unlock()
+
+	m := c.eventMonitor
+	if m == nil {
+		return nil
+	}
+	m.Lock()
+	for i, l := range m.listeners {
+		if l == listener {
+			m.listeners = append(m.listeners[:i], m.listeners[i+1:]...)
+			break
+		}
+	}
+	done := len(m.listeners) == 0
+	m.Unlock()
+
+	if done {
+		m.cancel()
+		c.eventMonitor = nil
+	}
+	return nil
+}
+
+func (c *Client) runEventMonitor(ctx context.Context, m *eventMonitor) {
+	backoff := time.Second
+	for {
+		err := c.listenEvents(ctx, m)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("events: reconnecting after error: %v", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > eventsReconnectMaxBackoff {
+			backoff = eventsReconnectMaxBackoff
+		}
+	}
+}
+
+func (c *Client) listenEvents(ctx context.Context, m *eventMonitor) error {
+	opts := m.opts
+	m.Lock()
+	if !m.lastSeen.IsZero() {
+		opts.Since = fmt.Sprintf("%d", m.lastSeen.Unix())
+	}
+	m.Unlock()
+
+	path := "/events?" + queryString(&opts)
+	resp, err := c.newStreamResponse(ctx, "GET", path, nil, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return decodeJSONStream(resp.Body, func(raw json.RawMessage) error {
+		var event APIEvents
+		if err := json.Unmarshal(raw, &event); err != nil {
+			return err
+		}
+		m.Lock()
+		m.lastSeen = time.Unix(event.Time, 0)
+		listeners := append([]chan<- *APIEvents(nil), m.listeners...)
+		m.Unlock()
+		for _, listener := range listeners {
+			select {
+			case listener <- &event:
+			default:
+			}
+		}
+		return nil
+	})
+}
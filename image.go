@@ -1,6 +1,7 @@
 package rudder
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
@@ -25,6 +26,14 @@ type AuthConfiguration struct {
 	Password      string `json:"password,omitempty"`
 	Email         string `json:"email,omitempty"`
 	ServerAddress string `json:"serveraddress,omitempty"`
+
+	// IdentityToken, when set, is used in place of Password for OAuth-style
+	// registry authentication and is passed through as-is.
+	IdentityToken string `json:"identitytoken,omitempty"`
+
+	// RegistryToken is a bearer token granted directly by the registry,
+	// bypassing the username/password exchange entirely.
+	RegistryToken string `json:"registrytoken,omitempty"`
 }
 
 // AuthConfigurations represents authentication options to use for the
@@ -33,6 +42,31 @@ type AuthConfigurations struct {
 	Configs map[string]AuthConfiguration `json:"configs"`
 }
 
+// AuthStatus represents the response from the /auth endpoint.
+//
+// http://goo.gl/6cOXnc
+type AuthStatus struct {
+	Status        string `json:"Status,omitempty"`
+	IdentityToken string `json:"IdentityToken,omitempty"`
+}
+
+// AuthCheck validates credentials against the registry and returns the
+// server's response, including an IdentityToken the caller can use in
+// place of a password on subsequent calls.
+//
+// http://goo.gl/6cOXnc
+func (c *Client) AuthCheck(auth AuthConfiguration) (AuthStatus, error) {
+	var status AuthStatus
+	body, _, err := c.do(context.Background(), "POST", "/auth", auth)
+	if err != nil {
+		return status, err
+	}
+	if err := json.Unmarshal(body, &status); err != nil {
+		return status, err
+	}
+	return status, nil
+}
+
 func headersWithAuth(auths ...interface{}) (map[string]string, error) {
 	headers := make(map[string]string)
 	for _, auth := range auths {
@@ -67,6 +101,10 @@ type BuildImageOption struct {
 	Auth          AuthConfiguration  `qs:"-"` // for older docker X-Registry-Auth header
 	AuthConfigs   AuthConfigurations `qs:"-"` // for newer docker X-Registry-Config header
 	ContextDir    string             `qs:"-"`
+
+	// Context, if set, lets callers cancel a build in progress. If nil,
+	// context.Background() is used.
+	Context context.Context `qs:"-"`
 }
 
 // BuildImage builds an image from a tarball's url or a Dockerfile in the input stream.
@@ -76,6 +114,10 @@ func (c *Client) BuildImage(opt BuildImageOption) error {
 	if opt.OutputStream == nil {
 		return ErrMissingOutputStream
 	}
+	ctx := opt.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
 
 	headers, err := headersWithAuth(opt.Auth, opt.AuthConfigs)
 	if err != nil {
@@ -96,6 +138,6 @@ func (c *Client) BuildImage(opt BuildImageOption) error {
 		}
 	}
 
-	return c.stream("POST", fmt.Sprintf("/build?%s",
+	return c.stream(ctx, "POST", fmt.Sprintf("/build?%s",
 		queryString(&opt)), true, opt.RawJSONStream, headers, opt.InputStream, opt.OutputStream, nil)
 }
@@ -0,0 +1,151 @@
+package rudder
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// ErrNetworkNotFound is returned when a given network does not exist.
+var ErrNetworkNotFound = errors.New("no such network")
+
+// Network represents a Docker network.
+//
+// http://goo.gl/6GugX9
+type Network struct {
+	Name       string              `json:"Name"`
+	ID         string              `json:"Id"`
+	Driver     string              `json:"Driver,omitempty"`
+	Scope      string              `json:"Scope,omitempty"`
+	IPAM       IPAMConfig          `json:"IPAM,omitempty"`
+	Containers map[string]Endpoint `json:"Containers,omitempty"`
+	Options    map[string]string   `json:"Options,omitempty"`
+	Labels     map[string]string   `json:"Labels,omitempty"`
+}
+
+// IPAMConfig represents IP Address Management configuration for a network.
+type IPAMConfig struct {
+	Driver string              `json:"Driver,omitempty"`
+	Config []map[string]string `json:"Config,omitempty"`
+}
+
+// Endpoint represents a container attached to a network.
+type Endpoint struct {
+	Name        string `json:"Name,omitempty"`
+	EndpointID  string `json:"EndpointID,omitempty"`
+	MacAddress  string `json:"MacAddress,omitempty"`
+	IPv4Address string `json:"IPv4Address,omitempty"`
+	IPv6Address string `json:"IPv6Address,omitempty"`
+}
+
+// ListNetworksOptions specifies parameters to the ListNetworks method.
+//
+// http://goo.gl/6GugX9
+type ListNetworksOptions struct {
+	Filters map[string][]string `qs:"filters"`
+}
+
+// ListNetworks returns a list of networks matching the given criteria.
+//
+// http://goo.gl/6GugX9
+func (c *Client) ListNetworks(opts ListNetworksOptions) ([]Network, error) {
+	path := "/networks?" + queryString(&opts)
+	body, _, err := c.do(context.Background(), "GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	var networks []Network
+	if err := json.Unmarshal(body, &networks); err != nil {
+		return nil, err
+	}
+	return networks, nil
+}
+
+// CreateNetworkOptions specifies parameters to the CreateNetwork method.
+//
+// http://goo.gl/6GugX9
+type CreateNetworkOptions struct {
+	Name    string            `json:"Name"`
+	Driver  string            `json:"Driver,omitempty"`
+	IPAM    IPAMConfig        `json:"IPAM,omitempty"`
+	Options map[string]string `json:"Options,omitempty"`
+	Labels  map[string]string `json:"Labels,omitempty"`
+}
+
+// CreateNetwork creates a network on the server.
+//
+// http://goo.gl/6GugX9
+func (c *Client) CreateNetwork(opts CreateNetworkOptions) (*Network, error) {
+	body, _, err := c.do(context.Background(), "POST", "/networks/create", opts)
+	if err != nil {
+		return nil, err
+	}
+	var created struct {
+		ID string `json:"Id"`
+	}
+	if err := json.Unmarshal(body, &created); err != nil {
+		return nil, err
+	}
+	return c.InspectNetwork(created.ID)
+}
+
+// InspectNetwork returns a network by its ID.
+//
+// http://goo.gl/6GugX9
+func (c *Client) InspectNetwork(id string) (*Network, error) {
+	body, status, err := c.do(context.Background(), "GET", "/networks/"+id, nil)
+	if status == http.StatusNotFound {
+		return nil, ErrNetworkNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var network Network
+	if err := json.Unmarshal(body, &network); err != nil {
+		return nil, err
+	}
+	return &network, nil
+}
+
+// RemoveNetwork removes a network by its ID.
+//
+// http://goo.gl/6GugX9
+func (c *Client) RemoveNetwork(id string) error {
+	_, status, err := c.do(context.Background(), "DELETE", "/networks/"+id, nil)
+	if status == http.StatusNotFound {
+		return ErrNetworkNotFound
+	}
+	return err
+}
+
+// NetworkConnectionOptions specifies parameters to ConnectNetwork and
+// DisconnectNetwork.
+//
+// http://goo.gl/6GugX9
+type NetworkConnectionOptions struct {
+	Container string `json:"Container"`
+	Force     bool   `json:"Force,omitempty"`
+}
+
+// ConnectNetwork attaches a container to a network.
+//
+// http://goo.gl/6GugX9
+func (c *Client) ConnectNetwork(id string, opts NetworkConnectionOptions) error {
+	_, status, err := c.do(context.Background(), "POST", "/networks/"+id+"/connect", opts)
+	if status == http.StatusNotFound {
+		return ErrNetworkNotFound
+	}
+	return err
+}
+
+// DisconnectNetwork detaches a container from a network.
+//
+// http://goo.gl/6GugX9
+func (c *Client) DisconnectNetwork(id string, opts NetworkConnectionOptions) error {
+	_, status, err := c.do(context.Background(), "POST", "/networks/"+id+"/disconnect", opts)
+	if status == http.StatusNotFound {
+		return ErrNetworkNotFound
+	}
+	return err
+}
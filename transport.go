@@ -0,0 +1,49 @@
+package rudder
+
+import "net/http"
+
+// Transport wraps a base http.RoundTripper and layers the headers every
+// Client request needs (User-Agent and MetaHeaders) on top of it, analogous
+// to the transport wrapper used by docker/distribution registry clients. It
+// lets callers plug in an instrumented or otherwise customized base
+// transport while still getting the client's standard headers for free.
+type Transport struct {
+	// Base is the underlying RoundTripper used to make requests. If nil,
+	// http.DefaultTransport is used.
+	Base http.RoundTripper
+
+	// MetaHeaders are set on every request issued through this transport,
+	// once, rather than having to be threaded through each call.
+	MetaHeaders http.Header
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = cloneRequest(req)
+	req.Header.Set("User-Agent", _USER_AGENT)
+	for key, values := range t.MetaHeaders {
+		for _, v := range values {
+			req.Header.Add(key, v)
+		}
+	}
+	return t.base().RoundTrip(req)
+}
+
+func (t *Transport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+// cloneRequest returns a shallow copy of req with a deep copy of its
+// headers, so callers can mutate the copy without racing the original.
+func cloneRequest(req *http.Request) *http.Request {
+	clone := new(http.Request)
+	*clone = *req
+	clone.Header = make(http.Header, len(req.Header))
+	for k, v := range req.Header {
+		clone.Header[k] = append([]string(nil), v...)
+	}
+	return clone
+}
@@ -0,0 +1,277 @@
+package rudder
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/docker/libtrust"
+)
+
+// LoadOrCreateTrustKey loads the JWK identity key stored at path, creating
+// a new EC P-256 key and persisting it there (along with its public half,
+// as "public-<basename>") if none exists yet. It's a thin wrapper around
+// libtrust's own helper of the same name, which already implements exactly
+// this behavior.
+func LoadOrCreateTrustKey(path string) (libtrust.PrivateKey, error) {
+	return libtrust.LoadOrCreateTrustKey(path)
+}
+
+// MigrateTrustKey moves the identity key at oldPath to newPath, leaving
+// newPath untouched if a key already lives there. It's meant for callers
+// upgrading from an older, differently-located trust key file.
+func MigrateTrustKey(oldPath, newPath string) error {
+	if _, err := os.Stat(newPath); err == nil {
+		return nil
+	}
+	key, err := libtrust.LoadKeyFile(oldPath)
+	if err != nil {
+		return fmt.Errorf("load trust key: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(newPath), 0700); err != nil {
+		return err
+	}
+	if err := libtrust.SaveKey(newPath, key); err != nil {
+		return fmt.Errorf("save trust key: %v", err)
+	}
+	return os.Remove(oldPath)
+}
+
+// signPayload produces a JWS over payload using key in libtrust's "pretty
+// signature" form (the payload JSON with a "signatures" array added),
+// suitable for attaching to a request as an X-Content-Trust-Signature header.
+func signPayload(key libtrust.PrivateKey, payload []byte) (string, error) {
+	sig, err := libtrust.NewJSONSignature(payload)
+	if err != nil {
+		return "", err
+	}
+	if err := sig.Sign(key); err != nil {
+		return "", err
+	}
+	pretty, err := sig.PrettySignature("signatures")
+	if err != nil {
+		return "", err
+	}
+	return string(pretty), nil
+}
+
+// verifyPayload checks sig (as produced by signPayload) against payload and
+// the given set of trusted public keys, returning the key ID that signed
+// it.
+func verifyPayload(payload []byte, sig string, trustedKeys []libtrust.PublicKey) (string, error) {
+	js, err := libtrust.ParsePrettySignature([]byte(sig), "signatures")
+	if err != nil {
+		return "", err
+	}
+	signed, err := js.Payload()
+	if err != nil {
+		return "", err
+	}
+	if !bytes.Equal(signed, payload) {
+		return "", fmt.Errorf("signature does not cover the expected manifest")
+	}
+	keys, err := js.Verify()
+	if err != nil {
+		return "", fmt.Errorf("verify signature: %v", err)
+	}
+	for _, key := range keys {
+		for _, trusted := range trustedKeys {
+			if key.KeyID() == trusted.KeyID() {
+				return key.KeyID(), nil
+			}
+		}
+	}
+	return "", fmt.Errorf("signature not signed by a trusted key")
+}
+
+// fetchCanonicalManifest fetches the daemon's inspect JSON for ref — the
+// closest thing to a manifest this client's stripped-down image API
+// exposes — and re-marshals it so the same image content always produces
+// identical bytes to sign/verify. encoding/json sorts map keys when
+// marshaling, so round-tripping through interface{} gives byte-stable
+// output independent of the field ordering or whitespace the daemon sent.
+func (c *Client) fetchCanonicalManifest(ctx context.Context, ref string) ([]byte, error) {
+	body, _, err := c.do(ctx, "GET", "/images/"+ref+"/json", nil)
+	if err != nil {
+		return nil, fmt.Errorf("fetch manifest: %v", err)
+	}
+	var parsed interface{}
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return nil, fmt.Errorf("parse manifest: %v", err)
+	}
+	canonical, err := json.Marshal(parsed)
+	if err != nil {
+		return nil, fmt.Errorf("canonicalize manifest: %v", err)
+	}
+	return canonical, nil
+}
+
+// PushImageOptions specifies parameters to the PushImage method.
+type PushImageOptions struct {
+	Name          string    `qs:"-"`
+	Tag           string    `qs:"tag"`
+	Registry      string    `qs:"-"`
+	OutputStream  io.Writer `qs:"-"`
+	RawJSONStream bool      `qs:"-"`
+
+	Context context.Context `qs:"-"`
+}
+
+// PushImage pushes an image to a registry, optionally signing it with
+// c.TrustKey when content trust is enabled. The signature is computed over
+// the canonicalized manifest (the daemon's inspect JSON for the pushed
+// reference) and sent as an X-Content-Trust-Signature header alongside the
+// usual push request; it does not implement full Notary/TUF trust
+// delegation, just client-side identity signing over the actual content.
+func (c *Client) PushImage(opts PushImageOptions, auth AuthConfiguration) error {
+	if opts.OutputStream == nil {
+		return ErrMissingOutputStream
+	}
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	headers, err := headersWithAuth(auth)
+	if err != nil {
+		return fmt.Errorf("marshal header: %v", err)
+	}
+
+	name := opts.Name
+	if opts.Registry != "" {
+		name = opts.Registry + "/" + opts.Name
+	}
+	if c.TrustKey != nil {
+		ref := name
+		if opts.Tag != "" {
+			ref += ":" + opts.Tag
+		}
+		manifest, err := c.fetchCanonicalManifest(ctx, ref)
+		if err != nil {
+			return fmt.Errorf("sign image: %v", err)
+		}
+		sig, err := signPayload(c.TrustKey, manifest)
+		if err != nil {
+			return fmt.Errorf("sign image manifest: %v", err)
+		}
+		headers["X-Content-Trust-Signature"] = sig
+	}
+
+	path := fmt.Sprintf("/images/%s/push?%s", name, queryString(&opts))
+	return c.stream(ctx, "POST", path, true, opts.RawJSONStream, headers, nil, opts.OutputStream, nil)
+}
+
+// PullImageOptions specifies parameters to the PullImage method.
+type PullImageOptions struct {
+	Repository    string    `qs:"fromImage"`
+	Tag           string    `qs:"tag"`
+	OutputStream  io.Writer `qs:"-"`
+	RawJSONStream bool      `qs:"-"`
+
+	// TrustedKeys, if non-empty, requires the pulled image to carry an
+	// X-Content-Trust-Signature response header verifiable against one of
+	// these public keys.
+	TrustedKeys []libtrust.PublicKey `qs:"-"`
+
+	Context context.Context `qs:"-"`
+}
+
+// PullImageResult carries metadata gathered while running PullImage.
+type PullImageResult struct {
+	// SignerKeyID is set when the pull was verified against TrustedKeys,
+	// identifying which key produced the signature.
+	SignerKeyID string
+}
+
+// PullImage pulls an image from a registry, verifying the content-trust
+// signature over its canonicalized manifest against opts.TrustedKeys when
+// provided.
+//
+// http://goo.gl/kbvJFI
+func (c *Client) PullImage(opts PullImageOptions, auth AuthConfiguration) (*PullImageResult, error) {
+	if opts.OutputStream == nil {
+		return nil, ErrMissingOutputStream
+	}
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	headers, err := headersWithAuth(auth)
+	if err != nil {
+		return nil, fmt.Errorf("marshal header: %v", err)
+	}
+
+	path := fmt.Sprintf("/images/create?%s", queryString(&opts))
+	resp, err := c.newStreamResponse(ctx, "POST", path, headers, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	sig := resp.Header.Get("X-Content-Trust-Signature")
+	if sig == "" && len(opts.TrustedKeys) > 0 {
+		return nil, fmt.Errorf("image was not signed, but trusted keys were provided")
+	}
+
+	result := &PullImageResult{}
+	if opts.RawJSONStream {
+		_, err = io.Copy(opts.OutputStream, resp.Body)
+	} else {
+		err = decodeJSONStream(resp.Body, func(raw json.RawMessage) error {
+			var m jsonMessage
+			if err := json.Unmarshal(raw, &m); err != nil {
+				return err
+			}
+			if m.Error != "" {
+				return errors.New(m.Error)
+			}
+			if m.Status != "" {
+				fmt.Fprintln(opts.OutputStream, m.Status)
+			}
+			return nil
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if sig != "" && len(opts.TrustedKeys) > 0 {
+		ref := opts.Repository
+		if opts.Tag != "" {
+			ref += ":" + opts.Tag
+		}
+		manifest, err := c.fetchCanonicalManifest(ctx, ref)
+		if err != nil {
+			return nil, fmt.Errorf("verify content trust signature: %v", err)
+		}
+		keyID, err := verifyPayload(manifest, sig, opts.TrustedKeys)
+		if err != nil {
+			return nil, fmt.Errorf("verify content trust signature: %v", err)
+		}
+		result.SignerKeyID = keyID
+	}
+	return result, nil
+}
+
+// Ping checks that the daemon is reachable. This codebase has no separate
+// registry Endpoint type to hang a Ping method off of (unlike the upstream
+// client this one is modeled on), so it lives on Client instead; when
+// TrustKey is set, the key ID is sent as X-Docker-Trust-Key-ID so servers
+// can pin the client's identity.
+func (c *Client) Ping() error {
+	headers := map[string]string{}
+	if c.TrustKey != nil {
+		headers["X-Docker-Trust-Key-ID"] = c.TrustKey.KeyID()
+	}
+	resp, err := c.newStreamResponse(context.Background(), "GET", "/_ping", headers, nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
@@ -0,0 +1,98 @@
+package rudder
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// ErrVolumeNotFound is returned when a given volume does not exist.
+var ErrVolumeNotFound = errors.New("no such volume")
+
+// Volume represents a Docker volume.
+//
+// http://goo.gl/3wgTV7
+type Volume struct {
+	Name       string            `json:"Name"`
+	Driver     string            `json:"Driver,omitempty"`
+	Mountpoint string            `json:"Mountpoint,omitempty"`
+	Labels     map[string]string `json:"Labels,omitempty"`
+}
+
+// ListVolumesOptions specifies parameters to the ListVolumes method.
+//
+// http://goo.gl/3wgTV7
+type ListVolumesOptions struct {
+	Filters map[string][]string `qs:"filters"`
+}
+
+// ListVolumes returns a list of volumes matching the given criteria.
+//
+// http://goo.gl/3wgTV7
+func (c *Client) ListVolumes(opts ListVolumesOptions) ([]Volume, error) {
+	path := "/volumes?" + queryString(&opts)
+	body, _, err := c.do(context.Background(), "GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result struct{ Volumes []Volume }
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, err
+	}
+	return result.Volumes, nil
+}
+
+// CreateVolumeOptions specifies parameters to the CreateVolume method.
+//
+// http://goo.gl/pBUbrz
+type CreateVolumeOptions struct {
+	Name       string            `json:"Name,omitempty"`
+	Driver     string            `json:"Driver,omitempty"`
+	DriverOpts map[string]string `json:"DriverOpts,omitempty"`
+	Labels     map[string]string `json:"Labels,omitempty"`
+}
+
+// CreateVolume creates a volume on the server.
+//
+// http://goo.gl/pBUbrz
+func (c *Client) CreateVolume(opts CreateVolumeOptions) (*Volume, error) {
+	body, _, err := c.do(context.Background(), "POST", "/volumes/create", opts)
+	if err != nil {
+		return nil, err
+	}
+	var volume Volume
+	if err := json.Unmarshal(body, &volume); err != nil {
+		return nil, err
+	}
+	return &volume, nil
+}
+
+// InspectVolume returns a volume by its name.
+//
+// http://goo.gl/0g9Qpg
+func (c *Client) InspectVolume(name string) (*Volume, error) {
+	body, status, err := c.do(context.Background(), "GET", "/volumes/"+name, nil)
+	if status == http.StatusNotFound {
+		return nil, ErrVolumeNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var volume Volume
+	if err := json.Unmarshal(body, &volume); err != nil {
+		return nil, err
+	}
+	return &volume, nil
+}
+
+// RemoveVolume removes a volume by its name.
+//
+// http://goo.gl/79RH9e
+func (c *Client) RemoveVolume(name string) error {
+	_, status, err := c.do(context.Background(), "DELETE", "/volumes/"+name, nil)
+	if status == http.StatusNotFound {
+		return ErrVolumeNotFound
+	}
+	return err
+}